@@ -19,6 +19,28 @@ type Client struct {
 	Client        *http.Client
 	MarshalFunc   func(v interface{}) ([]byte, error)
 	UnmarshalFunc func(data []byte, v interface{}) error
+	// RetryPolicy controls DoWithRetry/DoJsonWithRetry. A nil policy falls back to defaultRetryPolicy.
+	RetryPolicy *RetryPolicy
+	// PerCallTimeout, if set, bounds each individual attempt made by DoWithRetry/DoJsonWithRetry.
+	PerCallTimeout time.Duration
+	// MaxBodyBytes, if set, caps how much of a response body DoReq will buffer,
+	// returning an *ErrBodyTooLarge once exceeded. Use DoStream to bypass buffering entirely.
+	MaxBodyBytes int64
+
+	// middlewares is the chain Use appends to; DoReq and DoStream run requests through it.
+	middlewares []Middleware
+
+	// Codecs maps MIME type to the Codec used to (un)marshal request/response bodies
+	// of that type. A nil map falls back to defaultCodecs (JSON/XML/form/msgpack/protobuf).
+	Codecs map[string]Codec
+	// DefaultRequestType is the Content-Type used to marshal a request body that isn't
+	// already a string/[]byte/io.Reader, and isn't given an explicit Content-Type header.
+	// Defaults to "application/json".
+	DefaultRequestType string
+	// DefaultAcceptTypes is sent as the Accept header when the caller doesn't set one.
+	// Defaults to []string{"application/json"}, unless Codecs is set, in which case it
+	// defaults to that registry's MIME types.
+	DefaultAcceptTypes []string
 }
 
 func (c *Client) Do(method, url string, headers map[string]string, body interface{}) (*Response, error) {
@@ -50,18 +72,32 @@ func (c *Client) DoCtx(
 }
 
 func (c *Client) DoReq(req *http.Request) (*Response, error) {
-	resp, err := c.Client.Do(req)
+	resp, err := c.roundTrip(req)
 	if resp != nil && resp.Body != nil {
 		defer resp.Body.Close()
 	}
 	if err != nil {
 		return nil, err
 	}
-	respBody, err := ioutil.ReadAll(resp.Body)
+	respBody, err := c.readBody(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{Response: resp, body: respBody, UnmarshalFunc: c.UnmarshalFunc, Codecs: c.GetCodecs()}, nil
+}
+
+func (c *Client) readBody(body io.Reader) ([]byte, error) {
+	if c.MaxBodyBytes <= 0 {
+		return ioutil.ReadAll(body)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(body, c.MaxBodyBytes+1))
 	if err != nil {
 		return nil, err
 	}
-	return &Response{Response: resp, body: respBody, UnmarshalFunc: c.UnmarshalFunc}, nil
+	if int64(len(data)) > c.MaxBodyBytes {
+		return nil, &ErrBodyTooLarge{Limit: c.MaxBodyBytes}
+	}
+	return data, nil
 }
 
 func (c *Client) DoJson(method, url string, headers map[string]string, body, data interface{}) error {
@@ -88,10 +124,39 @@ func (c *Client) DoJsonCtx(
 	return resp.Json(data)
 }
 
+// DoTyped is Do augmented with explicit content negotiation: it marshals body with
+// the codec registered for mimeType (sent as the request's Content-Type), then
+// decodes the response into out using the codec for the response's Content-Type.
+func (c *Client) DoTyped(
+	method, url string, headers map[string]string, body, out interface{}, mimeType string,
+) error {
+	if mimeType != `` {
+		headers = withContentType(headers, mimeType)
+	}
+	resp, err := c.Do(method, url, headers, body)
+	if err != nil {
+		return err
+	}
+	if err := resp.Ok(); err != nil {
+		return err
+	}
+	return resp.Decode(out)
+}
+
+func withContentType(headers map[string]string, contentType string) map[string]string {
+	result := make(map[string]string, len(headers)+1)
+	for k, v := range headers {
+		result[k] = v
+	}
+	result[`Content-Type`] = contentType
+	return result
+}
+
 func (c *Client) makeReq(
 	method, url string, headers map[string]string, body interface{},
 ) (*http.Request, error) {
-	bodyReader, err := c.makeBodyReader(body)
+	contentType := c.requestContentType(headers)
+	bodyReader, usedDefaultCodec, err := c.makeBodyReader(body, contentType)
 	if err != nil {
 		return nil, err
 	}
@@ -105,14 +170,34 @@ func (c *Client) makeReq(
 	for k, v := range headers {
 		req.Header[k] = []string{v}
 	}
+	if usedDefaultCodec && req.Header.Get(`Content-Type`) == `` {
+		req.Header.Set(`Content-Type`, contentType)
+	}
+	if req.Header.Get(`Accept`) == `` {
+		req.Header.Set(`Accept`, strings.Join(c.GetDefaultAcceptTypes(), `, `))
+	}
 	return req, nil
 }
 
-func (c *Client) makeBodyReader(data interface{}) (io.Reader, error) {
+// requestContentType resolves the Content-Type to marshal a request body with:
+// the caller's explicit header if any, else c.GetDefaultRequestType().
+func (c *Client) requestContentType(headers map[string]string) string {
+	if ct := headers[`Content-Type`]; ct != `` {
+		return ct
+	}
+	return c.GetDefaultRequestType()
+}
+
+// makeBodyReader builds the request body reader for data, marshaling it with the
+// codec for contentType when data isn't already an io.Reader/string/[]byte. It
+// reports whether that default marshaling path was taken, so makeReq knows whether
+// to set the Content-Type header itself.
+func (c *Client) makeBodyReader(data interface{}, contentType string) (io.Reader, bool, error) {
 	if data == nil {
-		return nil, nil
+		return nil, false, nil
 	}
 	var reader io.Reader
+	var usedDefaultCodec bool
 	switch body := data.(type) {
 	case io.Reader:
 		reader = body
@@ -126,14 +211,15 @@ func (c *Client) makeBodyReader(data interface{}) (io.Reader, error) {
 		}
 	default:
 		if !isNil(body) {
-			buf, err := c.GetMarshalFunc()(body)
+			buf, err := c.codecFor(contentType).Marshal(body)
 			if err != nil {
-				return nil, err
+				return nil, false, err
 			}
 			reader = bytes.NewBuffer(buf)
+			usedDefaultCodec = true
 		}
 	}
-	return reader, nil
+	return reader, usedDefaultCodec, nil
 }
 
 func (c *Client) GetMarshalFunc() func(v interface{}) ([]byte, error) {
@@ -143,6 +229,13 @@ func (c *Client) GetMarshalFunc() func(v interface{}) ([]byte, error) {
 	return json.Marshal
 }
 
+func (c *Client) GetUnmarshalFunc() func(data []byte, v interface{}) error {
+	if c.UnmarshalFunc != nil {
+		return c.UnmarshalFunc
+	}
+	return json.Unmarshal
+}
+
 func isNil(data interface{}) bool {
 	v := reflect.ValueOf(data)
 	switch v.Kind() {
@@ -0,0 +1,171 @@
+package httputil
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"time"
+)
+
+// RoundTripFunc performs a single HTTP round trip, matching the signature of
+// (*http.Client).Do so middlewares can wrap either one.
+type RoundTripFunc func(*http.Request) (*http.Response, error)
+
+// Middleware wraps a RoundTripFunc with additional behavior.
+type Middleware func(RoundTripFunc) RoundTripFunc
+
+// Use appends middlewares to the chain DoReq runs requests through. Middlewares
+// run in the order they're passed, outermost first.
+func (c *Client) Use(middlewares ...Middleware) {
+	c.middlewares = append(c.middlewares, middlewares...)
+}
+
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	rt := RoundTripFunc(c.Client.Do)
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		rt = c.middlewares[i](rt)
+	}
+	return rt(req)
+}
+
+// drainBody reads body fully and returns two equivalent copies of it, so a
+// middleware can inspect one while leaving the other intact for the real request.
+func drainBody(body io.ReadCloser) (r1, r2 io.ReadCloser, err error) {
+	if body == nil || body == http.NoBody {
+		return http.NoBody, http.NoBody, nil
+	}
+	var buf bytes.Buffer
+	if _, err = buf.ReadFrom(body); err != nil {
+		return nil, nil, err
+	}
+	if err = body.Close(); err != nil {
+		return nil, nil, err
+	}
+	return ioutil.NopCloser(&buf), ioutil.NopCloser(bytes.NewReader(buf.Bytes())), nil
+}
+
+// DumpMiddleware dumps each request and response via log, using drainBody so the
+// original request/response streams are left intact for the rest of the chain.
+func DumpMiddleware(log func(dump []byte)) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Body != nil {
+				var forSend, forDump io.ReadCloser
+				var err error
+				if forSend, forDump, err = drainBody(req.Body); err != nil {
+					return nil, err
+				}
+				req.Body = forSend
+				if dump, err := httputil.DumpRequestOut(cloneRequestWithBody(req, forDump), true); err == nil {
+					log(dump)
+				}
+			} else if dump, err := httputil.DumpRequestOut(req, true); err == nil {
+				log(dump)
+			}
+
+			resp, err := next(req)
+			if err != nil || resp == nil {
+				return resp, err
+			}
+			var respCopy io.ReadCloser
+			resp.Body, respCopy, err = drainBody(resp.Body)
+			if err != nil {
+				return resp, err
+			}
+			if dump, err := httputil.DumpResponse(&http.Response{
+				Status: resp.Status, StatusCode: resp.StatusCode, Proto: resp.Proto,
+				ProtoMajor: resp.ProtoMajor, ProtoMinor: resp.ProtoMinor,
+				Header: resp.Header, Body: respCopy, ContentLength: resp.ContentLength,
+			}, true); err == nil {
+				log(dump)
+			}
+			resp.Body, _, err = drainBody(resp.Body)
+			return resp, err
+		}
+	}
+}
+
+func cloneRequestWithBody(req *http.Request, body io.ReadCloser) *http.Request {
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	return clone
+}
+
+// BearerAuthMiddleware sets the Authorization header to "Bearer <token>" on every request.
+func BearerAuthMiddleware(token string) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("Authorization", "Bearer "+token)
+			return next(req)
+		}
+	}
+}
+
+// BasicAuthMiddleware sets HTTP basic auth credentials on every request.
+func BasicAuthMiddleware(username, password string) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			req.SetBasicAuth(username, password)
+			return next(req)
+		}
+	}
+}
+
+// GzipMiddleware advertises gzip support and transparently decodes gzip-encoded
+// responses so callers never see Content-Encoding: gzip.
+func GzipMiddleware() Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("Accept-Encoding", "gzip")
+			resp, err := next(req)
+			if err != nil || resp == nil || resp.Header.Get("Content-Encoding") != "gzip" {
+				return resp, err
+			}
+			gz, err := gzip.NewReader(resp.Body)
+			if err != nil {
+				return resp, err
+			}
+			resp.Body = struct {
+				io.Reader
+				io.Closer
+			}{gz, resp.Body}
+			resp.Header.Del("Content-Encoding")
+			resp.Header.Del("Content-Length")
+			resp.ContentLength = -1
+			return resp, nil
+		}
+	}
+}
+
+// RequestIDMiddleware sets header to an id produced by gen on every request that
+// doesn't already carry one, for propagating a request id through to the server.
+func RequestIDMiddleware(header string, gen func() string) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get(header) == "" {
+				req.Header.Set(header, gen())
+			}
+			return next(req)
+		}
+	}
+}
+
+// MetricsMiddleware reports method, host, response status and duration of every
+// request to observe, in the style of a Prometheus HistogramVec/CounterVec callback.
+func MetricsMiddleware(observe func(method, host string, statusCode int, duration time.Duration)) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next(req)
+			statusCode := 0
+			if resp != nil {
+				statusCode = resp.StatusCode
+			}
+			observe(req.Method, req.URL.Host, statusCode, time.Since(start))
+			return resp, err
+		}
+	}
+}
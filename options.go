@@ -0,0 +1,114 @@
+package httputil
+
+import (
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"time"
+)
+
+// RedirectPolicy selects one of the built-in redirect behaviors for NewClient.
+type RedirectPolicy int
+
+const (
+	// Follow follows redirects using the standard library's default behavior
+	// (up to 10 redirects).
+	Follow RedirectPolicy = iota
+	// FollowSameHost follows redirects only while the redirect target's host
+	// matches the original request's host.
+	FollowSameHost
+	// None never follows redirects; the first response is returned as-is.
+	None
+)
+
+// ClientOptions configures the *http.Client NewClient builds, covering the cookie
+// jar, redirect policy and transport/dial tuning that callers would otherwise have
+// to wire up by hand.
+type ClientOptions struct {
+	BaseUrl string
+
+	// CookieJar, if true, gives the client an auto-populated net/http/cookiejar.Jar.
+	CookieJar bool
+
+	// RedirectPolicy selects a built-in redirect behavior. Ignored if CheckRedirect is set.
+	RedirectPolicy RedirectPolicy
+	// CheckRedirect, if set, overrides RedirectPolicy with custom redirect logic,
+	// passed straight through to http.Client.CheckRedirect.
+	CheckRedirect func(req *http.Request, via []*http.Request) error
+
+	// Timeout is the overall per-request timeout, passed straight through to http.Client.Timeout.
+	Timeout time.Duration
+
+	MaxIdleConns        int
+	MaxConnsPerHost     int
+	IdleConnTimeout     time.Duration
+	TLSHandshakeTimeout time.Duration
+	DisableCompression  bool
+	Proxy               func(*http.Request) (*url.URL, error)
+
+	DialTimeout time.Duration
+	KeepAlive   time.Duration
+}
+
+// NewClient builds a Client whose *http.Client is fully configured from opts.
+// The zero value of Client (Client{}) keeps working as before; NewClient is an
+// opt-in convenience for callers who want a jar, a redirect policy and transport
+// tuning wired up in one call.
+func NewClient(opts ClientOptions) *Client {
+	transport := &http.Transport{
+		Proxy: opts.Proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   durationOrDefault(opts.DialTimeout, 30*time.Second),
+			KeepAlive: durationOrDefault(opts.KeepAlive, 30*time.Second),
+		}).DialContext,
+		MaxIdleConns:        opts.MaxIdleConns,
+		MaxConnsPerHost:     opts.MaxConnsPerHost,
+		IdleConnTimeout:     durationOrDefault(opts.IdleConnTimeout, 90*time.Second),
+		TLSHandshakeTimeout: durationOrDefault(opts.TLSHandshakeTimeout, 10*time.Second),
+		DisableCompression:  opts.DisableCompression,
+	}
+	if transport.Proxy == nil {
+		transport.Proxy = http.ProxyFromEnvironment
+	}
+
+	httpClient := &http.Client{
+		Transport:     transport,
+		Timeout:       opts.Timeout,
+		CheckRedirect: opts.checkRedirect(),
+	}
+	if opts.CookieJar {
+		jar, _ := cookiejar.New(nil)
+		httpClient.Jar = jar
+	}
+
+	return &Client{BaseUrl: opts.BaseUrl, Client: httpClient}
+}
+
+func (opts ClientOptions) checkRedirect() func(req *http.Request, via []*http.Request) error {
+	if opts.CheckRedirect != nil {
+		return opts.CheckRedirect
+	}
+	switch opts.RedirectPolicy {
+	case None:
+		return func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	case FollowSameHost:
+		return func(req *http.Request, via []*http.Request) error {
+			if len(via) > 0 && req.URL.Host != via[0].URL.Host {
+				return http.ErrUseLastResponse
+			}
+			return nil
+		}
+	default:
+		return nil
+	}
+}
+
+func durationOrDefault(d, def time.Duration) time.Duration {
+	if d > 0 {
+		return d
+	}
+	return def
+}
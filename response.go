@@ -13,6 +13,8 @@ type Response struct {
 	*http.Response
 	body          []byte
 	UnmarshalFunc func(data []byte, v interface{}) error
+	// Codecs is the registry Decode picks a decoder from, keyed by MIME type.
+	Codecs map[string]Codec
 }
 
 func Get(url string, headers map[string]string, body interface{}) (*Response, error) {
@@ -96,8 +98,29 @@ func (resp *Response) Json(data interface{}) error {
 	if data == nil {
 		return nil
 	}
+	return resp.unmarshalAndValidate(resp.GetUnmarshalFunc(), data)
+}
+
+// Decode unmarshals the response body into data using the Codec registered for the
+// response's Content-Type, falling back to Json (and its UnmarshalFunc) for JSON
+// or when Content-Type is absent, so existing callers of Json are unaffected.
+func (resp *Response) Decode(data interface{}) error {
+	if data == nil {
+		return nil
+	}
+	mimeType := baseMimeType(resp.Header.Get("Content-Type"))
+	if mimeType == "" || mimeType == "application/json" {
+		return resp.Json(data)
+	}
+	codec, ok := resp.Codecs[mimeType]
+	if !ok {
+		return resp.Json(data)
+	}
+	return resp.unmarshalAndValidate(codec.Unmarshal, data)
+}
 
-	if err := resp.GetUnmarshalFunc()(resp.body, data); err != nil {
+func (resp *Response) unmarshalAndValidate(unmarshal func(data []byte, v interface{}) error, data interface{}) error {
+	if err := unmarshal(resp.body, data); err != nil {
 		return fmt.Errorf("%s: %s", err.Error(), string(resp.body))
 	}
 	if d, ok := data.(interface {
@@ -0,0 +1,216 @@
+package httputil
+
+import (
+	"context"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how Client.DoWithRetry and Client.DoJsonWithRetry retry failed attempts.
+type RetryPolicy struct {
+	MaxAttempts          int
+	BaseDelay            time.Duration
+	MaxDelay             time.Duration
+	JitterFactor         float64
+	RetriableStatusCodes []int
+	RetriableError       func(err error) bool
+}
+
+var defaultRetryPolicy = &RetryPolicy{
+	MaxAttempts:          3,
+	BaseDelay:            200 * time.Millisecond,
+	MaxDelay:             10 * time.Second,
+	JitterFactor:         1,
+	RetriableStatusCodes: []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+}
+
+func (c *Client) GetRetryPolicy() *RetryPolicy {
+	if c.RetryPolicy != nil {
+		return c.RetryPolicy
+	}
+	return defaultRetryPolicy
+}
+
+func (p *RetryPolicy) retriableStatus(code int) bool {
+	for _, c := range p.RetriableStatusCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *RetryPolicy) retriableErr(err error) bool {
+	if p.RetriableError != nil {
+		return p.RetriableError(err)
+	}
+	return err != nil
+}
+
+// backoff computes a full-jitter exponential backoff delay for the given attempt (0-based).
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	base, max := p.BaseDelay, p.MaxDelay
+	if base <= 0 {
+		base = defaultRetryPolicy.BaseDelay
+	}
+	if max <= 0 {
+		max = defaultRetryPolicy.MaxDelay
+	}
+	ceiling := float64(base) * float64(int64(1)<<uint(attempt))
+	if ceiling > float64(max) || ceiling <= 0 {
+		ceiling = float64(max)
+	}
+	jitter := p.JitterFactor
+	if jitter <= 0 {
+		jitter = 1
+	}
+	return time.Duration(rand.Float64() * jitter * ceiling)
+}
+
+// retryAfter parses a Retry-After header (seconds or HTTP-date) into a delay, if present.
+func retryAfter(resp *Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// DoWithRetry behaves like DoCtx, but consults c.GetRetryPolicy() and c.PerCallTimeout
+// and retries the request, resending the buffered body, until it succeeds or the
+// policy's attempts are exhausted or ctx is canceled.
+func (c *Client) DoWithRetry(
+	ctx context.Context, opName, method, url string, headers map[string]string, body interface{},
+) (*Response, error) {
+	contentType := c.requestContentType(headers)
+	bufBytes, usedDefaultCodec, err := c.bufferBody(body, contentType)
+	if err != nil {
+		return nil, err
+	}
+	if usedDefaultCodec {
+		headers = withContentType(headers, contentType)
+	}
+	policy := c.GetRetryPolicy()
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	var resp *Response
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if ctx != nil && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		attemptCtx := ctx
+		var cancel context.CancelFunc
+		if c.PerCallTimeout > 0 {
+			base := ctx
+			if base == nil {
+				base = context.Background()
+			}
+			attemptCtx, cancel = context.WithTimeout(base, c.PerCallTimeout)
+		}
+		resp, err = c.DoCtx(attemptCtx, attemptOpName(opName, attempt), method, url, headers, bodyForAttempt(bufBytes))
+		if cancel != nil {
+			cancel()
+		}
+
+		last := attempt == maxAttempts-1
+		if !shouldRetry(policy, resp, err) || last {
+			return resp, err
+		}
+
+		delay := policy.backoff(attempt)
+		if d, ok := retryAfter(resp); ok {
+			delay = d
+		}
+		if !sleepCtx(ctx, delay) {
+			return resp, err
+		}
+	}
+	return resp, err
+}
+
+// DoJsonWithRetry is DoWithRetry followed by Response.Ok and Response.Json, mirroring DoJsonCtx.
+func (c *Client) DoJsonWithRetry(
+	ctx context.Context, opName, method, url string, headers map[string]string, body, data interface{},
+) error {
+	resp, err := c.DoWithRetry(ctx, opName, method, url, headers, body)
+	if err != nil {
+		return err
+	}
+	if err := resp.Ok(); err != nil {
+		return err
+	}
+	return resp.Json(data)
+}
+
+func shouldRetry(policy *RetryPolicy, resp *Response, err error) bool {
+	if err != nil {
+		return policy.retriableErr(err)
+	}
+	return resp != nil && policy.retriableStatus(resp.StatusCode)
+}
+
+func bodyForAttempt(bufBytes []byte) interface{} {
+	if bufBytes == nil {
+		return nil
+	}
+	return bufBytes
+}
+
+func attemptOpName(opName string, attempt int) string {
+	return opName + " attempt=" + strconv.Itoa(attempt+1)
+}
+
+// sleepCtx waits for delay, returning false early if ctx is canceled first.
+func sleepCtx(ctx context.Context, delay time.Duration) bool {
+	if delay <= 0 {
+		return true
+	}
+	if ctx == nil {
+		time.Sleep(delay)
+		return true
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// bufferBody materializes body into bytes so it can be resent on each retry attempt,
+// marshaling it with the codec for contentType (the caller's resolved Content-Type)
+// rather than always defaulting to JSON. It reports whether that default marshaling
+// path was taken, mirroring makeBodyReader, so DoWithRetry knows whether it must
+// re-apply the Content-Type header itself on every attempt.
+func (c *Client) bufferBody(body interface{}, contentType string) ([]byte, bool, error) {
+	reader, usedDefaultCodec, err := c.makeBodyReader(body, contentType)
+	if err != nil {
+		return nil, false, err
+	}
+	if reader == nil {
+		return nil, usedDefaultCodec, nil
+	}
+	buf, err := ioutil.ReadAll(reader)
+	return buf, usedDefaultCodec, err
+}
@@ -0,0 +1,138 @@
+package httputil
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// StreamResponse wraps a live, unbuffered *http.Response for large downloads and
+// server-sent events. Unlike Response, it does not read the body into memory;
+// callers are responsible for closing it. Copy and Events close the body when they
+// finish; JSONDecoder does not, so the caller closes s.Body after decoding.
+type StreamResponse struct {
+	*http.Response
+	streamErr error
+}
+
+// Err returns any error encountered while scanning the stream for Events (including
+// a line exceeding the scanner's buffer), once the Events channel has been drained
+// and closed. It is nil for a clean EOF.
+func (s *StreamResponse) Err() error {
+	return s.streamErr
+}
+
+// Copy streams the response body into w and closes the body when done.
+func (s *StreamResponse) Copy(w io.Writer) (int64, error) {
+	defer s.Body.Close()
+	return io.Copy(w, s.Body)
+}
+
+// JSONDecoder returns a json.Decoder reading directly off the response body.
+// The caller is responsible for closing s.Body once done decoding.
+func (s *StreamResponse) JSONDecoder() *json.Decoder {
+	return json.NewDecoder(s.Body)
+}
+
+// Event is a single server-sent event, per the text/event-stream format.
+type Event struct {
+	ID    string
+	Event string
+	Data  string
+	Retry int
+}
+
+// Events parses the response body as a text/event-stream and returns a channel of
+// Event values. The channel is closed, and the response body closed, once the
+// stream ends or a read error occurs.
+func (s *StreamResponse) Events() <-chan Event {
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		defer s.Body.Close()
+
+		scanner := bufio.NewScanner(s.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+		var cur Event
+		var data []string
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line == "" {
+				if len(data) > 0 {
+					cur.Data = strings.Join(data, "\n")
+					events <- cur
+				}
+				cur, data = Event{}, nil
+				continue
+			}
+			field, value := splitSSEField(line)
+			switch field {
+			case "event":
+				cur.Event = value
+			case "data":
+				data = append(data, value)
+			case "id":
+				cur.ID = value
+			case "retry":
+				if n, err := strconv.Atoi(value); err == nil {
+					cur.Retry = n
+				}
+			}
+		}
+		s.streamErr = scanner.Err()
+	}()
+	return events
+}
+
+func splitSSEField(line string) (field, value string) {
+	idx := strings.IndexByte(line, ':')
+	if idx < 0 {
+		return line, ""
+	}
+	field = line[:idx]
+	value = strings.TrimPrefix(line[idx+1:], " ")
+	return field, value
+}
+
+// ErrBodyTooLarge is returned by Client.DoReq when the response body exceeds
+// Client.MaxBodyBytes.
+type ErrBodyTooLarge struct {
+	Limit int64
+}
+
+func (e *ErrBodyTooLarge) Error() string {
+	return fmt.Sprintf("httputil: response body exceeds MaxBodyBytes limit of %d bytes", e.Limit)
+}
+
+// DoStream performs req without buffering the response body, returning a
+// StreamResponse the caller can Copy, decode or iterate as SSE.
+func (c *Client) DoStream(req *http.Request) (*StreamResponse, error) {
+	resp, err := c.roundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	return &StreamResponse{Response: resp}, nil
+}
+
+// GetStream issues a GET request and returns its response unbuffered.
+func GetStream(url string, headers map[string]string, body interface{}) (*StreamResponse, error) {
+	return DefaultClient.doStream(http.MethodGet, url, headers, body)
+}
+
+// PostStream issues a POST request and returns its response unbuffered.
+func PostStream(url string, headers map[string]string, body interface{}) (*StreamResponse, error) {
+	return DefaultClient.doStream(http.MethodPost, url, headers, body)
+}
+
+func (c *Client) doStream(method, url string, headers map[string]string, body interface{}) (*StreamResponse, error) {
+	req, err := c.makeReq(method, url, headers, body)
+	if err != nil {
+		return nil, err
+	}
+	return c.DoStream(req)
+}
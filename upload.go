@@ -0,0 +1,128 @@
+package httputil
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/lovego/tracer"
+)
+
+// FileUpload is one file part of a multipart/form-data upload built by
+// Client.Upload/UploadCtx.
+type FileUpload struct {
+	FieldName   string
+	Filename    string
+	ContentType string
+	Reader      io.Reader
+}
+
+// Upload sends fields and files as a multipart/form-data request.
+func (c *Client) Upload(
+	method, url string, headers map[string]string, fields map[string]string, files []FileUpload,
+) (*Response, error) {
+	req, err := c.makeUploadReq(method, url, headers, fields, files)
+	if err != nil {
+		return nil, err
+	}
+	return c.DoReq(req)
+}
+
+// UploadCtx is Upload with a context, traced like DoCtx.
+func (c *Client) UploadCtx(
+	ctx context.Context, opName, method, url string, headers map[string]string,
+	fields map[string]string, files []FileUpload,
+) (*Response, error) {
+	req, err := c.makeUploadReq(method, url, headers, fields, files)
+	if err != nil {
+		return nil, err
+	}
+	if ctx != nil {
+		ctx = tracer.StartChild(ctx, opName)
+		defer tracer.Finish(ctx)
+		if tracer.Get(ctx) != nil {
+			var gotFirstResponseByteTime *time.Time
+			ctx, gotFirstResponseByteTime = httpTrace(ctx)
+			defer logTimeSpent(ctx, "Read", *gotFirstResponseByteTime)
+		}
+		req = req.WithContext(ctx)
+	}
+	return c.DoReq(req)
+}
+
+func (c *Client) makeUploadReq(
+	method, url string, headers map[string]string, fields map[string]string, files []FileUpload,
+) (*http.Request, error) {
+	body, contentType := multipartBody(fields, files)
+	if c.BaseUrl != `` {
+		url = c.BaseUrl + url
+	}
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header[k] = []string{v}
+	}
+	req.Header.Set(`Content-Type`, contentType)
+	return req, nil
+}
+
+// multipartBody streams fields and files into a multipart/form-data body via an
+// io.Pipe, so large file uploads aren't buffered into memory up front.
+func multipartBody(fields map[string]string, files []FileUpload) (io.Reader, string) {
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	go func() {
+		err := writeMultipart(writer, fields, files)
+		if closeErr := writer.Close(); err == nil {
+			err = closeErr
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr, writer.FormDataContentType()
+}
+
+func writeMultipart(writer *multipart.Writer, fields map[string]string, files []FileUpload) error {
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			return err
+		}
+	}
+	for _, file := range files {
+		part, err := createFilePart(writer, file)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(part, file.Reader); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createFilePart(writer *multipart.Writer, file FileUpload) (io.Writer, error) {
+	if file.ContentType == `` {
+		return writer.CreateFormFile(file.FieldName, file.Filename)
+	}
+	header := make(textproto.MIMEHeader)
+	header.Set(`Content-Disposition`, fmt.Sprintf(
+		`form-data; name="%s"; filename="%s"`, escapeQuotes(file.FieldName), escapeQuotes(file.Filename),
+	))
+	header.Set(`Content-Type`, file.ContentType)
+	return writer.CreatePart(header)
+}
+
+// escapeQuotes mirrors mime/multipart's unexported escapeQuotes, so FieldName and
+// Filename containing `"` or a backslash can't break out of the quoted
+// Content-Disposition parameter the way CreateFormFile itself guards against.
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}